@@ -0,0 +1,167 @@
+package coolpack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coollabsio/coolpack/pkg/app"
+	"github.com/coollabsio/coolpack/pkg/dockerclient"
+	"github.com/coollabsio/coolpack/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	buildPath      string
+	buildTag       string
+	buildPlatforms []string
+	buildArgs      []string
+	buildEnv       string
+)
+
+var buildCmd = &cobra.Command{
+	Use:   "build [path]",
+	Short: "Build a Docker image from the generated plan",
+	Long: `Build the application at the given path (or current directory) into a
+Docker image. If the environment's .coolpack/env/<name>/Dockerfile is missing
+or older than the source, prepare is run first to (re)generate it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBuild,
+}
+
+func init() {
+	buildCmd.Flags().StringVarP(&buildPath, "path", "p", "", "Path to the application (defaults to current directory)")
+	buildCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Image tag (defaults to the directory name)")
+	buildCmd.Flags().StringArrayVar(&buildPlatforms, "platform", nil, "Target platform for the build (e.g. linux/amd64); only one is supported, there's no buildx driver wired up for multi-arch manifest lists")
+	buildCmd.Flags().StringArrayVar(&buildArgs, "build-arg", nil, "Additional build-time variable (KEY=value), merged with plan.BuildEnv")
+	buildCmd.Flags().StringVar(&buildEnv, "env", "", "Environment to build (defaults to the active environment)")
+	rootCmd.AddCommand(buildCmd)
+}
+
+func runBuild(cmd *cobra.Command, args []string) error {
+	absPath, envName, plan, err := buildEnsurePlan(buildPath, buildEnv, args)
+	if err != nil {
+		return err
+	}
+
+	tag := buildTag
+	if tag == "" {
+		tag = filepath.Base(absPath)
+	}
+
+	opts, err := buildOptions(absPath, envName, tag, plan)
+	if err != nil {
+		return err
+	}
+
+	adapter, err := dockerclient.New()
+	if err != nil {
+		return fmt.Errorf("failed to connect to docker: %w", err)
+	}
+
+	fmt.Printf("Building %s (env: %s)\n", tag, envName)
+	return adapter.Build(context.Background(), opts, os.Stdout)
+}
+
+// buildOptions assembles the dockerclient.BuildOptions shared by `build`
+// and `run`. Dockerfile must be given relative to ContextDir: the Engine
+// API build endpoint resolves it against the tar archiveContext uploads,
+// which writes its entries relative to ContextDir, not as absolute paths.
+func buildOptions(absPath, envName, tag string, plan *app.Plan) (dockerclient.BuildOptions, error) {
+	buildArgMap := make(map[string]string, len(plan.BuildEnv)+len(buildArgs))
+	for k, v := range plan.BuildEnv {
+		buildArgMap[k] = v
+	}
+	for _, arg := range buildArgs {
+		if idx := strings.Index(arg, "="); idx != -1 {
+			buildArgMap[arg[:idx]] = arg[idx+1:]
+		}
+	}
+
+	relDockerfile, err := filepath.Rel(absPath, filepath.Join(workspace.EnvDir(absPath, envName), "Dockerfile"))
+	if err != nil {
+		return dockerclient.BuildOptions{}, fmt.Errorf("failed to resolve Dockerfile path: %w", err)
+	}
+
+	return dockerclient.BuildOptions{
+		ContextDir: absPath,
+		Dockerfile: relDockerfile,
+		Tag:        tag,
+		Platforms:  buildPlatforms,
+		BuildArgs:  buildArgMap,
+	}, nil
+}
+
+// buildEnsurePlan resolves the app path and environment, runs prepare if
+// that environment's Dockerfile is missing or stale relative to the source
+// tree, and returns the absolute path, environment name, and resolved plan
+// so build/run don't require callers to know about the .coolpack layout.
+func buildEnsurePlan(path, envName string, args []string) (string, string, *app.Plan, error) {
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		path = "."
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	if envName == "" {
+		envName, err = workspace.GetActive(absPath)
+		if err != nil {
+			return "", "", nil, err
+		}
+	}
+
+	dockerfilePath := filepath.Join(workspace.EnvDir(absPath, envName), "Dockerfile")
+	if buildIsStale(absPath, dockerfilePath) {
+		prepareEnv = envName
+		if _, err := prepareGenerate(absPath); err != nil {
+			return "", "", nil, fmt.Errorf("failed to prepare: %w", err)
+		}
+	}
+
+	plan, err := buildLoadResolvedPlan(absPath, envName)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return absPath, envName, plan, nil
+}
+
+// buildIsStale reports whether dockerfilePath is missing or older than any
+// source file under absPath (excluding .coolpack itself).
+func buildIsStale(absPath, dockerfilePath string) bool {
+	info, err := os.Stat(dockerfilePath)
+	if err != nil {
+		return true
+	}
+
+	stale := false
+	_ = filepath.Walk(absPath, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || stale {
+			return nil
+		}
+		if fi.IsDir() && filepath.Base(path) == ".coolpack" {
+			return filepath.SkipDir
+		}
+		if !fi.IsDir() && fi.ModTime().After(info.ModTime()) {
+			stale = true
+		}
+		return nil
+	})
+
+	return stale
+}
+
+// buildLoadResolvedPlan loads the plan.json that prepare wrote under the
+// environment's directory alongside its Dockerfile, so build/run see the
+// fully resolved plan (post command overrides, post SPA/package settings).
+func buildLoadResolvedPlan(absPath, envName string) (*app.Plan, error) {
+	return prepareLoadPlanFromFile(filepath.Join(workspace.EnvDir(absPath, envName), "plan.json"))
+}
@@ -0,0 +1,129 @@
+package coolpack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// prepareViper backs every prepare setting that used to be a scattered
+// os.Getenv("COOLPACK_*") call. Precedence, enforced by Viper itself once a
+// setting is bound via BindPFlag, is: CLI flag > COOLPACK_* env var >
+// project ./coolpack.yaml > user config (XDG_CONFIG_HOME or ~/.coolpackrc).
+// Whatever's left unset falls through to each prepareApply* function's
+// existing auto-detected default.
+var prepareViper = viper.New()
+
+// prepareBoundFlags maps each Viper key to the prepareCmd flag name it's
+// bound to. Flags with slice values (--packages) aren't listed here: Viper's
+// pflag binding doesn't merge repeated array flags cleanly, so packages are
+// still combined by hand in prepareApplyCustomPackages.
+var prepareBoundFlags = map[string]string{
+	"install_cmd":   "install-cmd",
+	"build_cmd":     "build-cmd",
+	"start_cmd":     "start-cmd",
+	"static_server": "static-server",
+	"output_dir":    "output-dir",
+	"spa":           "spa",
+	"no_spa":        "no-spa",
+}
+
+// prepareLoadConfig merges the user and project config files into
+// prepareViper and (re-)binds it to prepareCmd's flags. It's safe to call on
+// every run: BindPFlag and MergeInConfig are both idempotent for our
+// purposes here.
+func prepareLoadConfig(absPath string) error {
+	prepareViper.SetEnvPrefix("COOLPACK")
+	prepareViper.AutomaticEnv()
+	// The output directory keeps its documented COOLPACK_SPA_OUTPUT_DIR name
+	// rather than the COOLPACK_OUTPUT_DIR that AutomaticEnv would derive.
+	if err := prepareViper.BindEnv("output_dir", "COOLPACK_SPA_OUTPUT_DIR"); err != nil {
+		return fmt.Errorf("failed to bind COOLPACK_SPA_OUTPUT_DIR: %w", err)
+	}
+
+	if err := prepareMergeUserConfig(); err != nil {
+		return fmt.Errorf("failed to read user config: %w", err)
+	}
+	if err := prepareMergeProjectConfig(absPath); err != nil {
+		return fmt.Errorf("failed to read coolpack.yaml: %w", err)
+	}
+
+	for key, flag := range prepareBoundFlags {
+		if err := prepareViper.BindPFlag(key, prepareCmd.Flags().Lookup(flag)); err != nil {
+			return fmt.Errorf("failed to bind --%s: %w", flag, err)
+		}
+	}
+
+	return nil
+}
+
+// prepareMergeUserConfig merges the first user-level config file found, in
+// order: $XDG_CONFIG_HOME/coolpack/config.yaml (falling back to
+// ~/.config/coolpack/config.yaml if XDG_CONFIG_HOME isn't set), then
+// $HOME/.coolpackrc.
+func prepareMergeUserConfig() error {
+	var candidates []string
+	home, homeErr := os.UserHomeDir()
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		candidates = append(candidates, filepath.Join(xdg, "coolpack", "config.yaml"))
+	} else if homeErr == nil {
+		candidates = append(candidates, filepath.Join(home, ".config", "coolpack", "config.yaml"))
+	}
+	if homeErr == nil {
+		candidates = append(candidates, filepath.Join(home, ".coolpackrc"))
+	}
+
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		// .coolpackrc has no recognized extension, so Viper can't infer its
+		// format from SetConfigFile alone; the other candidates are plain
+		// .yaml files and don't need this.
+		if filepath.Base(path) == ".coolpackrc" {
+			prepareViper.SetConfigType("yaml")
+		}
+		prepareViper.SetConfigFile(path)
+		return prepareViper.MergeInConfig()
+	}
+	return nil
+}
+
+// prepareMergeProjectConfig merges ./coolpack.yaml, if present, on top of
+// whatever user config was already merged in.
+func prepareMergeProjectConfig(absPath string) error {
+	path := filepath.Join(absPath, "coolpack.yaml")
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	prepareViper.SetConfigFile(path)
+	return prepareViper.MergeInConfig()
+}
+
+// prepareConfigPackages reads the "packages" setting from env/config, which
+// may be a YAML list (project/user config) or a comma-separated string
+// (the COOLPACK_PACKAGES env var).
+func prepareConfigPackages() []string {
+	switch v := prepareViper.Get("packages").(type) {
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		var out []string
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package coolpack
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/coollabsio/coolpack/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+var envPath string
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage .coolpack environments",
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List environments and show which one is active",
+	Args:  cobra.NoArgs,
+	RunE:  runEnvList,
+}
+
+var envUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the active environment",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEnvUse,
+}
+
+var envNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a new environment",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEnvNew,
+}
+
+func init() {
+	envCmd.PersistentFlags().StringVarP(&envPath, "path", "p", "", "Path to the application (defaults to current directory)")
+	envCmd.AddCommand(envListCmd, envUseCmd, envNewCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnvList(cmd *cobra.Command, args []string) error {
+	absPath, err := envResolvePath(envPath, nil)
+	if err != nil {
+		return err
+	}
+
+	envs, err := workspace.List(absPath)
+	if err != nil {
+		return err
+	}
+	if len(envs) == 0 {
+		fmt.Println("No environments yet, run \"coolpack init\" or \"coolpack env new <name>\"")
+		return nil
+	}
+
+	active, err := workspace.GetActive(absPath)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range envs {
+		marker := "  "
+		if name == active {
+			marker = "* "
+		}
+		fmt.Printf("%s%s\n", marker, name)
+	}
+	return nil
+}
+
+func runEnvUse(cmd *cobra.Command, args []string) error {
+	absPath, err := envResolvePath(envPath, nil)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	envs, err := workspace.List(absPath)
+	if err != nil {
+		return err
+	}
+	if !contains(envs, name) {
+		return fmt.Errorf("environment %q does not exist, run \"coolpack env new %s\" first", name, name)
+	}
+
+	if err := workspace.SetActive(absPath, name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Active environment set to %q\n", name)
+	return nil
+}
+
+func runEnvNew(cmd *cobra.Command, args []string) error {
+	absPath, err := envResolvePath(envPath, nil)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if err := workspace.New(absPath, name); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created environment %q\n", name)
+	return nil
+}
+
+// envResolvePath resolves the path argument shared by init/env/* commands:
+// --path if given, else the first positional arg, else the current directory.
+func envResolvePath(flagPath string, args []string) (string, error) {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if flagPath != "" {
+		path = flagPath
+	}
+	return filepath.Abs(path)
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
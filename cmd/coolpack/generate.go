@@ -0,0 +1,14 @@
+package coolpack
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Project the resolved plan into additional output formats",
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+}
@@ -0,0 +1,82 @@
+package coolpack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coollabsio/coolpack/pkg/kube"
+	"github.com/coollabsio/coolpack/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateKubePath        string
+	generateKubeEnv         string
+	generateKubeReplicas    int
+	generateKubeNamespace   string
+	generateKubeImage       string
+	generateKubeIngressHost string
+)
+
+var generateKubeCmd = &cobra.Command{
+	Use:   "kube [path]",
+	Short: "Emit a Kubernetes Deployment and Service for the resolved plan",
+	Long: `Take the already-computed plan (from detection or --env's resolved
+plan.json) and project it into a Deployment + Service, and optionally an
+Ingress, written to .coolpack/k8s/ so it can be applied directly with
+"kubectl apply -f .coolpack/k8s/".`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGenerateKube,
+}
+
+func init() {
+	generateKubeCmd.Flags().StringVarP(&generateKubePath, "path", "p", "", "Path to the application (defaults to current directory)")
+	generateKubeCmd.Flags().StringVar(&generateKubeEnv, "env", "", "Environment whose resolved plan to use (defaults to the active environment)")
+	generateKubeCmd.Flags().IntVar(&generateKubeReplicas, "replicas", 1, "Number of pod replicas")
+	generateKubeCmd.Flags().StringVar(&generateKubeNamespace, "namespace", "default", "Kubernetes namespace")
+	generateKubeCmd.Flags().StringVar(&generateKubeImage, "image", "", "Image to deploy (defaults to the directory name)")
+	generateKubeCmd.Flags().StringVar(&generateKubeIngressHost, "ingress-host", "", "Host to route to the service via an Ingress (omitted if unset)")
+	generateCmd.AddCommand(generateKubeCmd)
+}
+
+func runGenerateKube(cmd *cobra.Command, args []string) error {
+	absPath, envName, plan, err := buildEnsurePlan(generateKubePath, generateKubeEnv, args)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(absPath)
+	image := generateKubeImage
+	if image == "" {
+		image = name
+	}
+
+	gen := kube.New(plan, kube.Options{
+		Name:        name,
+		Namespace:   generateKubeNamespace,
+		Image:       image,
+		Replicas:    generateKubeReplicas,
+		IngressHost: generateKubeIngressHost,
+	})
+
+	manifests, err := gen.GenerateManifests()
+	if err != nil {
+		return fmt.Errorf("failed to generate Kubernetes manifests: %w", err)
+	}
+
+	k8sDir := filepath.Join(workspace.Root(absPath), "k8s")
+	if err := os.MkdirAll(k8sDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .coolpack/k8s directory: %w", err)
+	}
+
+	fmt.Printf("Generated files in %s (env: %s):\n", k8sDir, envName)
+	for filename, content := range manifests {
+		if err := os.WriteFile(filepath.Join(k8sDir, filename), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filename, err)
+		}
+		fmt.Printf("  - %s\n", filename)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package coolpack
+
+import (
+	"fmt"
+
+	"github.com/coollabsio/coolpack/pkg/workspace"
+	"github.com/spf13/cobra"
+)
+
+var initPath string
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize a .coolpack workspace",
+	Long: `Create the .coolpack workspace for the application at the given path
+(or current directory), with a single "dev" environment set as active. Use
+"coolpack env new" to add additional environments such as staging or prod.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().StringVarP(&initPath, "path", "p", "", "Path to the application (defaults to current directory)")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	absPath, err := envResolvePath(initPath, args)
+	if err != nil {
+		return err
+	}
+
+	envs, err := workspace.List(absPath)
+	if err != nil {
+		return err
+	}
+	if len(envs) > 0 {
+		return fmt.Errorf(".coolpack workspace already initialized (environments: %v)", envs)
+	}
+
+	if err := workspace.New(absPath, workspace.DefaultEnv); err != nil {
+		return err
+	}
+	if err := workspace.SetActive(absPath, workspace.DefaultEnv); err != nil {
+		return err
+	}
+
+	fmt.Printf("Initialized .coolpack workspace with environment %q\n", workspace.DefaultEnv)
+	return nil
+}
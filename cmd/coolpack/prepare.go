@@ -3,6 +3,7 @@ package coolpack
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/coollabsio/coolpack/pkg/app"
 	"github.com/coollabsio/coolpack/pkg/detector"
 	"github.com/coollabsio/coolpack/pkg/generator"
+	"github.com/coollabsio/coolpack/pkg/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +27,10 @@ var (
 	prepareNoSPA        bool
 	preparePackages     []string
 	preparePlanFile     string
+	prepareWatch        bool
+	prepareEnv          string
+	prepareJSON         bool
+	prepareOutput       string
 )
 
 var prepareCmd = &cobra.Command{
@@ -35,7 +41,30 @@ detect the language, framework, and package manager, then generate
 a Dockerfile and related build files in the .coolpack directory.
 
 If a coolpack.json file exists in the project root, it will be used
-instead of running detection. Use --plan to specify a different file.
+instead of running detection. Use --plan to specify a different file, or
+--plan - to read a plan JSON document from stdin.
+
+--json prints the fully-resolved plan as JSON to stdout instead of writing
+it to disk, so it can be piped into CI pipelines or other tooling:
+
+  coolpack prepare --json | jq '.startCommand'
+  coolpack prepare --json | jq '...' | coolpack prepare --plan - --output build/
+
+--output redirects the generated Dockerfile and plan.json to a directory of
+your choosing instead of .coolpack/env/<name>.
+
+The .coolpack directory is a workspace of named environments (see
+"coolpack env"). --env selects which environment's values.yaml is merged
+over the detected plan before generation; it defaults to the active
+environment (coolpack env use), or "dev" if none has been created yet.
+
+Install/build/start commands, the static file server, output directory, and
+packages can each be set, in order of precedence, by: CLI flag, COOLPACK_*
+environment variable, ./coolpack.yaml in the project root, or a user config
+file ($XDG_CONFIG_HOME/coolpack/config.yaml or $HOME/.coolpackrc). Whatever
+isn't set anywhere falls back to the auto-detected default. Packages given
+with --packages are added to, not replacing, the packages from those other
+sources.
 
 Environment Variables:
   COOLPACK_INSTALL_CMD     Override install command
@@ -46,7 +75,12 @@ Environment Variables:
   COOLPACK_STATIC_SERVER   Static file server: caddy (default), nginx
   COOLPACK_SPA_OUTPUT_DIR  Override static output directory (e.g., dist, build)
   COOLPACK_SPA             Enable SPA mode (serves index.html for all routes)
-  COOLPACK_PACKAGES        Additional APT packages (comma-separated)`,
+  COOLPACK_PACKAGES        Additional APT packages (comma-separated)
+
+With --watch, coolpack keeps running and regenerates the Dockerfile whenever
+a source file under the analyzed path changes, debouncing rapid save bursts
+and honoring .coolpackignore (gitignore-style patterns) to avoid reacting to
+its own output or noisy directories like node_modules and .git.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPrepare,
 }
@@ -63,6 +97,10 @@ func init() {
 	prepareCmd.Flags().BoolVar(&prepareNoSPA, "no-spa", false, "Disable SPA mode (overrides auto-detection)")
 	prepareCmd.Flags().StringArrayVar(&preparePackages, "packages", nil, "Additional APT packages to install (e.g., curl, wget)")
 	prepareCmd.Flags().StringVar(&preparePlanFile, "plan", "", "Use plan file instead of detection (e.g., coolpack.json)")
+	prepareCmd.Flags().BoolVarP(&prepareWatch, "watch", "w", false, "Watch the path and regenerate the Dockerfile on change")
+	prepareCmd.Flags().StringVar(&prepareEnv, "env", "", "Environment to generate for, merging its values.yaml over the plan (defaults to the active environment)")
+	prepareCmd.Flags().BoolVar(&prepareJSON, "json", false, "Print the fully-resolved plan as JSON to stdout instead of writing it to disk")
+	prepareCmd.Flags().StringVar(&prepareOutput, "output", "", "Directory to write the Dockerfile and plan.json to (defaults to .coolpack/env/<name>)")
 }
 
 func runPrepare(cmd *cobra.Command, args []string) error {
@@ -86,6 +124,103 @@ func runPrepare(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path does not exist: %s", absPath)
 	}
 
+	if _, err := prepareGenerate(absPath); err != nil {
+		return err
+	}
+
+	if !prepareWatch {
+		return nil
+	}
+
+	return prepareWatchAndRegenerate(absPath)
+}
+
+// prepareGenerate runs detection (or loads a plan file), applies overrides,
+// and writes the Dockerfile for the given absolute path. It reports whether
+// the Dockerfile contents changed so callers like watch mode can skip
+// invalidating the downstream docker build cache on a no-op run.
+func prepareGenerate(absPath string) (bool, error) {
+	envName := prepareEnv
+	if envName == "" {
+		var err error
+		envName, err = workspace.GetActive(absPath)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	plan, err := prepareResolvePlan(absPath, envName)
+	if err != nil {
+		return false, err
+	}
+
+	if prepareJSON {
+		planJSON, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal plan: %w", err)
+		}
+		fmt.Println(string(planJSON))
+		return false, nil
+	}
+
+	// Artifacts go to .coolpack/env/<name> by default, or --output if given.
+	coolpackDir := workspace.EnvDir(absPath, envName)
+	if prepareOutput != "" {
+		coolpackDir, err = filepath.Abs(prepareOutput)
+		if err != nil {
+			return false, fmt.Errorf("failed to resolve --output: %w", err)
+		}
+	}
+	if err := os.MkdirAll(coolpackDir, 0755); err != nil {
+		return false, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Generate Dockerfile
+	gen := generator.New(plan)
+	dockerfile, err := gen.GenerateDockerfile()
+	if err != nil {
+		return false, fmt.Errorf("failed to generate Dockerfile: %w", err)
+	}
+
+	// Skip the write if the contents haven't changed so we don't bust the
+	// docker build cache on a no-op regeneration (e.g. in --watch mode).
+	dockerfilePath := filepath.Join(coolpackDir, "Dockerfile")
+	changed := true
+	if existing, err := os.ReadFile(dockerfilePath); err == nil && string(existing) == dockerfile {
+		changed = false
+	}
+
+	if changed {
+		if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
+			return false, fmt.Errorf("failed to write Dockerfile: %w", err)
+		}
+	}
+
+	// Persist the fully-resolved plan alongside the Dockerfile so other
+	// commands (build, run, generate kube) don't need to re-run detection.
+	planJSON, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(coolpackDir, "plan.json"), planJSON, 0644); err != nil {
+		return false, fmt.Errorf("failed to write plan.json: %w", err)
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	fmt.Printf("Generated files in %s:\n", coolpackDir)
+	fmt.Printf("  - Dockerfile\n")
+
+	return true, nil
+}
+
+// prepareResolvePlan loads a plan (from --plan, coolpack.json, or detection),
+// merges the named environment's values.yaml over it, and applies all
+// CLI/env overrides (which take precedence over both), returning the
+// fully-resolved plan.
+func prepareResolvePlan(absPath, envName string) (*app.Plan, error) {
 	var plan *app.Plan
 
 	// Check for plan file: --plan flag > coolpack.json in project root
@@ -97,13 +232,21 @@ func runPrepare(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if planFile != "" {
+	if planFile == "-" {
+		// Read plan JSON from stdin
+		fmt.Fprintln(os.Stderr, "Using plan from stdin")
+		var err error
+		plan, err = prepareLoadPlanFromReader(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plan from stdin: %w", err)
+		}
+	} else if planFile != "" {
 		// Load plan from file
 		fmt.Printf("Using plan file: %s\n", planFile)
 		var err error
 		plan, err = prepareLoadPlanFromFile(planFile)
 		if err != nil {
-			return fmt.Errorf("failed to load plan file: %w", err)
+			return nil, fmt.Errorf("failed to load plan file: %w", err)
 		}
 	} else {
 		// Run detection
@@ -111,58 +254,67 @@ func runPrepare(cmd *cobra.Command, args []string) error {
 		var err error
 		plan, err = d.Detect()
 		if err != nil {
-			return fmt.Errorf("detection failed: %w", err)
+			return nil, fmt.Errorf("detection failed: %w", err)
 		}
 
 		if plan == nil {
-			return fmt.Errorf("no supported application detected")
+			return nil, fmt.Errorf("no supported application detected")
 		}
 	}
 
-	// Apply command overrides (CLI > env > detected)
-	prepareApplyCommandOverrides(plan, prepareInstallCmd, prepareBuildCmd, prepareStartCmd)
+	// Merge the environment's values.yaml over the detected/loaded plan
+	values, err := workspace.LoadValues(absPath, envName)
+	if err != nil {
+		return nil, err
+	}
+	values.Apply(plan)
 
-	// Apply static server setting (CLI > env > default)
-	prepareApplyStaticServerSetting(plan, prepareStaticServer)
+	// Load coolpack.yaml / user config and bind it to prepareCmd's flags, so
+	// the prepareApply* calls below see a single source of truth for CLI
+	// flag > env var > project config > user config precedence.
+	if err := prepareLoadConfig(absPath); err != nil {
+		return nil, err
+	}
+
+	// Apply command overrides (CLI > env > project config > user config > detected)
+	prepareApplyCommandOverrides(plan)
+
+	// Apply static server setting (CLI > env > project config > user config > default)
+	prepareApplyStaticServerSetting(plan)
 
-	// Apply SPA setting (CLI > env > auto-detected)
-	prepareApplySPASetting(plan, prepareSPA, prepareNoSPA)
+	// Apply SPA setting (CLI > env > project config > user config > auto-detected)
+	prepareApplySPASetting(plan)
 
-	// Apply output directory override (CLI > env > framework default)
-	prepareApplyOutputDirSetting(plan, prepareOutputDir)
+	// Apply output directory override (CLI > env > project config > user config > framework default)
+	prepareApplyOutputDirSetting(plan)
 
-	// Apply custom packages (CLI > env > detected)
+	// Apply custom packages (CLI > env > project config > user config > detected)
 	prepareApplyCustomPackages(plan, preparePackages)
 
-	// Parse build environment variables
-	envMap := prepareParseEnvVars(prepareBuildEnvs)
-	if len(envMap) > 0 {
-		plan.BuildEnv = envMap
-	}
+	// Apply build environment variables (CLI --build-env merges on top of any
+	// build_args values.Apply already set from the environment's values.yaml)
+	prepareApplyBuildEnvOverrides(plan, prepareBuildEnvs)
 
-	// Create .coolpack directory
-	coolpackDir := filepath.Join(absPath, ".coolpack")
-	if err := os.MkdirAll(coolpackDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .coolpack directory: %w", err)
-	}
+	return plan, nil
+}
 
-	// Generate Dockerfile
-	gen := generator.New(plan)
-	dockerfile, err := gen.GenerateDockerfile()
-	if err != nil {
-		return fmt.Errorf("failed to generate Dockerfile: %w", err)
+// prepareApplyBuildEnvOverrides merges --build-env KEY=value (or bare KEY to
+// forward the current process's env var) into plan.BuildEnv, the same
+// additive pattern prepareApplyCustomPackages uses for custom_packages: it
+// must not replace whatever values.Apply already put there from the
+// environment's values.yaml.
+func prepareApplyBuildEnvOverrides(plan *app.Plan, envArgs []string) {
+	envMap := prepareParseEnvVars(envArgs)
+	if len(envMap) == 0 {
+		return
 	}
 
-	// Write Dockerfile
-	dockerfilePath := filepath.Join(coolpackDir, "Dockerfile")
-	if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
-		return fmt.Errorf("failed to write Dockerfile: %w", err)
+	if plan.BuildEnv == nil {
+		plan.BuildEnv = make(map[string]string, len(envMap))
+	}
+	for k, v := range envMap {
+		plan.BuildEnv[k] = v
 	}
-
-	fmt.Printf("Generated files in %s:\n", coolpackDir)
-	fmt.Printf("  - Dockerfile\n")
-
-	return nil
 }
 
 // prepareParseEnvVars parses environment variable arguments
@@ -182,87 +334,68 @@ func prepareParseEnvVars(envArgs []string) map[string]string {
 	return result
 }
 
-// prepareApplyCommandOverrides applies command overrides from CLI flags or env vars
-// Priority: CLI flags > Environment variables > Auto-detected
-func prepareApplyCommandOverrides(plan *detector.Plan, installCmd, buildCmd, startCmd string) {
-	// Install command: CLI > env > detected
-	if installCmd != "" {
-		plan.InstallCommand = installCmd
-	} else if env := os.Getenv("COOLPACK_INSTALL_CMD"); env != "" {
-		plan.InstallCommand = env
+// prepareApplyCommandOverrides applies command overrides from prepareViper.
+// Priority: CLI flag > env var > project config > user config > detected
+func prepareApplyCommandOverrides(plan *app.Plan) {
+	if v := prepareViper.GetString("install_cmd"); v != "" {
+		plan.InstallCommand = v
 	}
-
-	// Build command: CLI > env > detected
-	if buildCmd != "" {
-		plan.BuildCommand = buildCmd
-	} else if env := os.Getenv("COOLPACK_BUILD_CMD"); env != "" {
-		plan.BuildCommand = env
+	if v := prepareViper.GetString("build_cmd"); v != "" {
+		plan.BuildCommand = v
 	}
-
-	// Start command: CLI > env > detected
-	if startCmd != "" {
-		plan.StartCommand = startCmd
-	} else if env := os.Getenv("COOLPACK_START_CMD"); env != "" {
-		plan.StartCommand = env
+	if v := prepareViper.GetString("start_cmd"); v != "" {
+		plan.StartCommand = v
 	}
 }
 
-// prepareApplyStaticServerSetting applies static server setting from CLI or env var
-// Priority: CLI flag > Environment variable > default (caddy)
-func prepareApplyStaticServerSetting(plan *detector.Plan, staticServer string) {
+// prepareApplyStaticServerSetting applies the static server setting from
+// prepareViper. Priority: CLI flag > env var > project config > user
+// config > default (caddy)
+func prepareApplyStaticServerSetting(plan *app.Plan) {
 	if plan.Metadata == nil {
 		plan.Metadata = make(map[string]interface{})
 	}
 
-	if staticServer != "" {
-		plan.Metadata["static_server"] = staticServer
-	} else if env := os.Getenv("COOLPACK_STATIC_SERVER"); env != "" {
-		plan.Metadata["static_server"] = env
+	if v := prepareViper.GetString("static_server"); v != "" {
+		plan.Metadata["static_server"] = v
 	}
 	// Default is "caddy" which is handled in generator
 }
 
-// prepareApplySPASetting applies SPA setting from CLI or env var
-// Priority: --no-spa/COOLPACK_NO_SPA > --spa/COOLPACK_SPA > auto-detected
-func prepareApplySPASetting(plan *detector.Plan, spa bool, noSPA bool) {
+// prepareApplySPASetting applies the SPA setting from prepareViper.
+// Priority: --no-spa/COOLPACK_NO_SPA (and their config equivalents) >
+// --spa/COOLPACK_SPA > auto-detected
+func prepareApplySPASetting(plan *app.Plan) {
 	if plan.Metadata == nil {
 		plan.Metadata = make(map[string]interface{})
 	}
 
-	// --no-spa and COOLPACK_NO_SPA take highest priority
-	if noSPA {
-		delete(plan.Metadata, "is_spa")
-		return
-	}
-	if env := os.Getenv("COOLPACK_NO_SPA"); env == "true" || env == "1" {
+	if prepareViper.GetBool("no_spa") {
 		delete(plan.Metadata, "is_spa")
 		return
 	}
 
-	if spa {
-		plan.Metadata["is_spa"] = true
-	} else if env := os.Getenv("COOLPACK_SPA"); env == "true" || env == "1" {
+	if prepareViper.GetBool("spa") {
 		plan.Metadata["is_spa"] = true
 	}
 	// Auto-detected value is already in metadata from provider
 }
 
-// prepareApplyOutputDirSetting applies output directory override from CLI or env var
-// Priority: CLI flag > Environment variable > framework default (handled in generator)
-func prepareApplyOutputDirSetting(plan *detector.Plan, outputDir string) {
+// prepareApplyOutputDirSetting applies the output directory override from
+// prepareViper. Priority: CLI flag > env var > project config > user
+// config > framework default (handled in generator)
+func prepareApplyOutputDirSetting(plan *app.Plan) {
 	if plan.Metadata == nil {
 		plan.Metadata = make(map[string]interface{})
 	}
 
-	if outputDir != "" {
-		plan.Metadata["output_dir_override"] = outputDir
-	} else if env := os.Getenv("COOLPACK_SPA_OUTPUT_DIR"); env != "" {
-		plan.Metadata["output_dir_override"] = env
+	if v := prepareViper.GetString("output_dir"); v != "" {
+		plan.Metadata["output_dir_override"] = v
 	}
 }
 
 // prepareApplyCustomPackages adds custom APT packages to the plan (merges with existing)
-func prepareApplyCustomPackages(plan *detector.Plan, packages []string) {
+func prepareApplyCustomPackages(plan *app.Plan, packages []string) {
 	if plan.Metadata == nil {
 		plan.Metadata = make(map[string]interface{})
 	}
@@ -284,15 +417,9 @@ func prepareApplyCustomPackages(plan *detector.Plan, packages []string) {
 		customPackages = append(customPackages, packages...)
 	}
 
-	// Add environment variable packages (comma-separated)
-	if env := os.Getenv("COOLPACK_PACKAGES"); env != "" {
-		for _, pkg := range strings.Split(env, ",") {
-			pkg = strings.TrimSpace(pkg)
-			if pkg != "" {
-				customPackages = append(customPackages, pkg)
-			}
-		}
-	}
+	// Add env var / config file packages (CLI takes precedence by being listed first,
+	// but all are merged and deduplicated below)
+	customPackages = append(customPackages, prepareConfigPackages()...)
 
 	if len(customPackages) == 0 {
 		return
@@ -317,11 +444,23 @@ func prepareLoadPlanFromFile(path string) (*app.Plan, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return prepareParsePlanJSON(data)
+}
 
+// prepareLoadPlanFromReader loads a build plan from a JSON document, e.g.
+// os.Stdin for --plan -.
+func prepareLoadPlanFromReader(r io.Reader) (*app.Plan, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+	return prepareParsePlanJSON(data)
+}
+
+func prepareParsePlanJSON(data []byte) (*app.Plan, error) {
 	var plan app.Plan
 	if err := json.Unmarshal(data, &plan); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
-
 	return &plan, nil
 }
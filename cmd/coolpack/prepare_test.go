@@ -0,0 +1,38 @@
+package coolpack
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coollabsio/coolpack/pkg/app"
+)
+
+// TestPrepareApplyBuildEnvOverridesMerges guards the regression fixed in
+// prepareResolvePlan: --build-env used to do plan.BuildEnv = envMap, which
+// silently dropped whatever the environment's values.yaml build_args had
+// already set via workspace.Values.Apply.
+func TestPrepareApplyBuildEnvOverridesMerges(t *testing.T) {
+	plan := &app.Plan{
+		BuildEnv: map[string]string{"FROM_VALUES_YAML": "kept", "OVERRIDE_ME": "old"},
+	}
+
+	prepareApplyBuildEnvOverrides(plan, []string{"OVERRIDE_ME=new", "FROM_CLI=added"})
+
+	want := map[string]string{
+		"FROM_VALUES_YAML": "kept",
+		"OVERRIDE_ME":      "new",
+		"FROM_CLI":         "added",
+	}
+	if !reflect.DeepEqual(plan.BuildEnv, want) {
+		t.Errorf("plan.BuildEnv = %v, want %v", plan.BuildEnv, want)
+	}
+}
+
+func TestPrepareApplyBuildEnvOverridesNoArgsLeavesBuildEnvNil(t *testing.T) {
+	plan := &app.Plan{}
+	prepareApplyBuildEnvOverrides(plan, nil)
+
+	if plan.BuildEnv != nil {
+		t.Errorf("plan.BuildEnv = %v, want nil when no --build-env is given", plan.BuildEnv)
+	}
+}
@@ -0,0 +1,19 @@
+package coolpack
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "coolpack",
+	Short: "Detect, build, and run applications without writing a Dockerfile",
+}
+
+func init() {
+	rootCmd.AddCommand(prepareCmd)
+}
+
+// Execute runs the root command.
+func Execute() error {
+	return rootCmd.Execute()
+}
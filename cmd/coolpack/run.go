@@ -0,0 +1,70 @@
+package coolpack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/coollabsio/coolpack/pkg/dockerclient"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runPath    string
+	runTag     string
+	runPublish []string
+	runEnvFile string
+	runEnv     string
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [path]",
+	Short: "Build and run the application in a container",
+	Long: `Build the application at the given path (or current directory), as
+"coolpack build" does, then start a container from the resulting image.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRun,
+}
+
+func init() {
+	runCmd.Flags().StringVarP(&runPath, "path", "p", "", "Path to the application (defaults to current directory)")
+	runCmd.Flags().StringVarP(&runTag, "tag", "t", "", "Image tag (defaults to the directory name)")
+	runCmd.Flags().StringArrayVar(&runPublish, "publish", nil, "Publish a container port to the host (host:container)")
+	runCmd.Flags().StringVar(&runEnvFile, "env-file", "", "Read environment variables from a file")
+	runCmd.Flags().StringVar(&runEnv, "env", "", "Environment to build and run (defaults to the active environment)")
+	rootCmd.AddCommand(runCmd)
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	absPath, envName, plan, err := buildEnsurePlan(runPath, runEnv, args)
+	if err != nil {
+		return err
+	}
+
+	tag := runTag
+	if tag == "" {
+		tag = filepath.Base(absPath)
+	}
+
+	opts, err := buildOptions(absPath, envName, tag, plan)
+	if err != nil {
+		return err
+	}
+
+	adapter, err := dockerclient.New()
+	if err != nil {
+		return fmt.Errorf("failed to connect to docker: %w", err)
+	}
+
+	fmt.Printf("Building %s (env: %s)\n", tag, envName)
+	if err := adapter.Build(context.Background(), opts, os.Stdout); err != nil {
+		return err
+	}
+
+	return adapter.Run(context.Background(), dockerclient.RunOptions{
+		Image:   tag,
+		Publish: runPublish,
+		EnvFile: runEnvFile,
+	}, os.Stdout)
+}
@@ -0,0 +1,132 @@
+package coolpack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	gitignore "github.com/sabhiram/go-gitignore"
+)
+
+// prepareWatchDebounce is how long to wait after the last filesystem event
+// before regenerating, so a burst of saves (e.g. a formatter rewriting a
+// file) triggers a single regeneration instead of one per event.
+const prepareWatchDebounce = 300 * time.Millisecond
+
+// defaultCoolpackIgnore patterns are always applied, in addition to anything
+// found in .coolpackignore, so the watcher never reacts to its own output or
+// to version control metadata.
+var defaultCoolpackIgnore = []string{".git", ".coolpack", "node_modules"}
+
+// prepareWatchAndRegenerate watches absPath for file changes and re-runs
+// prepareGenerate whenever something relevant changes, debouncing rapid
+// bursts and skipping paths matched by .coolpackignore.
+func prepareWatchAndRegenerate(absPath string) error {
+	ignore, err := prepareLoadIgnore(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to load .coolpackignore: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := prepareWatchAddDirs(watcher, absPath, absPath, ignore); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", absPath, err)
+	}
+
+	fmt.Printf("Watching %s for changes (Ctrl+C to stop)\n", absPath)
+
+	var debounce *time.Timer
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			rel, err := filepath.Rel(absPath, event.Name)
+			if err != nil || ignore.MatchesPath(rel) {
+				continue
+			}
+
+			// A new directory needs to be watched too, since fsnotify isn't
+			// recursive on its own.
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = prepareWatchAddDirs(watcher, absPath, event.Name, ignore)
+				}
+			}
+
+			if debounce == nil {
+				debounce = time.NewTimer(prepareWatchDebounce)
+			} else {
+				debounce.Reset(prepareWatchDebounce)
+			}
+		case <-prepareWatchTimerC(debounce):
+			debounce = nil
+			if _, err := prepareGenerate(absPath); err != nil {
+				fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "watch: %v\n", err)
+		}
+	}
+}
+
+// prepareWatchTimerC returns t.C, or nil when t is nil so the surrounding
+// select simply skips that case until a debounce timer is armed.
+func prepareWatchTimerC(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
+// prepareWatchAddDirs recursively registers dir and its non-ignored
+// subdirectories with watcher. root is always the project root passed to
+// prepareWatchAndRegenerate (absPath), not dir itself — .coolpackignore
+// patterns are anchored to root, so a subdirectory discovered mid-watch must
+// still have its rel path computed against root, not against dir, or
+// root-anchored patterns (e.g. "/dist") stop matching past the initial walk.
+func prepareWatchAddDirs(watcher *fsnotify.Watcher, root, dir string, ignore *gitignore.GitIgnore) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		if path != root {
+			rel, err := filepath.Rel(root, path)
+			if err == nil && ignore.MatchesPath(rel) {
+				return filepath.SkipDir
+			}
+		}
+		return watcher.Add(path)
+	})
+}
+
+// prepareLoadIgnore reads .coolpackignore from the project root, if present,
+// and combines it with the always-ignored defaults (.git, .coolpack,
+// node_modules) so detection changes in those directories never trigger a
+// feedback loop of regeneration.
+func prepareLoadIgnore(absPath string) (*gitignore.GitIgnore, error) {
+	lines := append([]string{}, defaultCoolpackIgnore...)
+
+	ignoreFile := filepath.Join(absPath, ".coolpackignore")
+	if data, err := os.ReadFile(ignoreFile); err == nil {
+		lines = append(lines, strings.Split(string(data), "\n")...)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return gitignore.CompileIgnoreLines(lines...), nil
+}
@@ -0,0 +1,36 @@
+// Package dockerclient wraps the Docker Engine API behind a small Adapter
+// interface, mirroring the adapter pattern the Docker CLI uses to keep its
+// subcommands independent of the underlying client implementation. The
+// daemon-facing pieces (cliAdapter, buildImage, runImage) aren't unit tested
+// here since they need a live daemon to exercise; the pure helpers buildImage
+// calls (archiveContext, buildPlatform, buildArgPointers) are covered instead.
+package dockerclient
+
+import (
+	"context"
+	"io"
+)
+
+// BuildOptions configures an image build.
+type BuildOptions struct {
+	ContextDir string
+	Dockerfile string
+	Tag        string
+	Platforms  []string
+	BuildArgs  map[string]string
+}
+
+// RunOptions configures a container run of a previously built image.
+type RunOptions struct {
+	Image   string
+	Publish []string
+	EnvFile string
+}
+
+// Adapter wraps the subset of the Docker Engine API that coolpack needs,
+// mirroring the adapter pattern the Docker CLI uses to keep its subcommands
+// independent of the underlying client implementation.
+type Adapter interface {
+	Build(ctx context.Context, opts BuildOptions, out io.Writer) error
+	Run(ctx context.Context, opts RunOptions, out io.Writer) error
+}
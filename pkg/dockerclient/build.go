@@ -0,0 +1,129 @@
+package dockerclient
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+)
+
+// buildImage tars up opts.ContextDir and streams it to the Docker Engine API
+// build endpoint, writing the daemon's progress output to out.
+func buildImage(ctx context.Context, cli *client.Client, opts BuildOptions, out io.Writer) error {
+	platform, err := buildPlatform(opts.Platforms)
+	if err != nil {
+		return err
+	}
+
+	buildCtx, err := archiveContext(opts.ContextDir)
+	if err != nil {
+		return fmt.Errorf("failed to archive build context: %w", err)
+	}
+
+	resp, err := cli.ImageBuild(ctx, buildCtx, types.ImageBuildOptions{
+		Dockerfile:  opts.Dockerfile,
+		Tags:        []string{opts.Tag},
+		Platform:    platform,
+		BuildArgs:   buildArgPointers(opts.BuildArgs),
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return fmt.Errorf("docker build failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return fmt.Errorf("failed to stream build output: %w", err)
+	}
+
+	return nil
+}
+
+// buildPlatform returns the single platform to pass to the classic
+// ImageBuild endpoint. That endpoint (and the BuildKit session behind it
+// here) produces one image for one platform, not a multi-arch manifest list
+// — there's no buildx driver wired up — so more than one --platform value is
+// rejected rather than silently building for only the first one.
+func buildPlatform(platforms []string) (string, error) {
+	if len(platforms) == 0 {
+		return "", nil
+	}
+	if len(platforms) > 1 {
+		return "", fmt.Errorf("only one --platform is supported (no buildx driver is configured for multi-arch manifest lists)")
+	}
+	return platforms[0], nil
+}
+
+func buildArgPointers(args map[string]string) map[string]*string {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make(map[string]*string, len(args))
+	for k, v := range args {
+		v := v
+		out[k] = &v
+	}
+	return out
+}
+
+// archiveContext tars the given directory into the stream ImageBuild
+// expects as its build context.
+func archiveContext(dir string) (io.Reader, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,95 @@
+package dockerclient
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// archiveContext's tar entry names are what BuildOptions.Dockerfile must be
+// relative to (the Engine API resolves Dockerfile against the uploaded
+// context, not the host filesystem) — this guards against that path getting
+// out of sync again.
+func TestArchiveContextWritesContextRelativeNames(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".coolpack", "env", "dev"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".coolpack", "env", "dev", "Dockerfile"), []byte("FROM scratch\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := archiveContext(dir)
+	if err != nil {
+		t.Fatalf("archiveContext() error = %v", err)
+	}
+
+	var names []string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading tar: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+
+	wantDockerfile := ".coolpack/env/dev/Dockerfile"
+	found := false
+	for _, name := range names {
+		if name == wantDockerfile {
+			found = true
+		}
+		if filepath.IsAbs(name) {
+			t.Errorf("tar entry %q is an absolute path, want context-relative", name)
+		}
+	}
+	if !found {
+		t.Errorf("tar entries = %v, want to contain %q", names, wantDockerfile)
+	}
+}
+
+func TestBuildPlatform(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      []string
+		want    string
+		wantErr bool
+	}{
+		{"empty", nil, "", false},
+		{"single", []string{"linux/amd64"}, "linux/amd64", false},
+		{"multi", []string{"linux/amd64", "linux/arm64"}, "", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := buildPlatform(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("buildPlatform(%v) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("buildPlatform(%v) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildArgPointers(t *testing.T) {
+	if got := buildArgPointers(nil); got != nil {
+		t.Errorf("buildArgPointers(nil) = %v, want nil", got)
+	}
+
+	args := map[string]string{"NODE_ENV": "production"}
+	out := buildArgPointers(args)
+	v, ok := out["NODE_ENV"]
+	if !ok || v == nil || *v != "production" {
+		t.Errorf("buildArgPointers(%v) = %v, want a pointer to %q", args, out, "production")
+	}
+}
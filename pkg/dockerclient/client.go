@@ -0,0 +1,33 @@
+package dockerclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/client"
+)
+
+// cliAdapter is the default Adapter, backed by the real Docker Engine API
+// client.
+type cliAdapter struct {
+	cli *client.Client
+}
+
+// New returns an Adapter backed by the Docker Engine API client configured
+// from the environment (DOCKER_HOST, DOCKER_CERT_PATH, etc.).
+func New() (Adapter, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client: %w", err)
+	}
+	return &cliAdapter{cli: cli}, nil
+}
+
+func (a *cliAdapter) Build(ctx context.Context, opts BuildOptions, out io.Writer) error {
+	return buildImage(ctx, a.cli, opts, out)
+}
+
+func (a *cliAdapter) Run(ctx context.Context, opts RunOptions, out io.Writer) error {
+	return runImage(ctx, a.cli, opts, out)
+}
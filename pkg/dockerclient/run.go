@@ -0,0 +1,136 @@
+package dockerclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/nat"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// runImage creates and starts a container from opts.Image, publishing any
+// requested ports and streaming its logs to out until it exits.
+func runImage(ctx context.Context, cli *client.Client, opts RunOptions, out io.Writer) error {
+	env, err := readEnvFile(opts.EnvFile)
+	if err != nil {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	exposed, bindings, err := parsePublish(opts.Publish)
+	if err != nil {
+		return fmt.Errorf("failed to parse --publish: %w", err)
+	}
+
+	created, err := cli.ContainerCreate(ctx, &container.Config{
+		Image:        opts.Image,
+		Env:          env,
+		ExposedPorts: exposed,
+	}, &container.HostConfig{
+		PortBindings: bindings,
+	}, nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to create container: %w", err)
+	}
+
+	if err := cli.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start container: %w", err)
+	}
+
+	fmt.Fprintf(out, "Started container %s\n", created.ID[:12])
+	return streamLogsUntilExit(ctx, cli, created.ID, out)
+}
+
+// streamLogsUntilExit follows the container's combined stdout/stderr to out
+// and blocks until it exits, so `coolpack run` behaves like `docker run`
+// rather than returning while the container is still starting up.
+func streamLogsUntilExit(ctx context.Context, cli *client.Client, containerID string, out io.Writer) error {
+	logs, err := cli.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream container logs: %w", err)
+	}
+	defer logs.Close()
+
+	waitCh, errCh := cli.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(out, out, logs)
+		done <- err
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to wait for container: %w", err)
+	case <-waitCh:
+	}
+
+	if err := <-done; err != nil && err != io.EOF {
+		return fmt.Errorf("failed to stream container logs: %w", err)
+	}
+
+	return nil
+}
+
+// readEnvFile loads KEY=value lines from an --env-file, skipping blank lines
+// and comments.
+func readEnvFile(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var env []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		env = append(env, line)
+	}
+	return env, nil
+}
+
+// parsePublish turns --publish host:container[/proto] flags into the
+// exposed-ports and port-bindings maps the container API expects.
+func parsePublish(publish []string) (nat.PortSet, nat.PortMap, error) {
+	exposed := nat.PortSet{}
+	bindings := nat.PortMap{}
+
+	for _, p := range publish {
+		parts := strings.SplitN(p, ":", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("invalid --publish %q, expected host:container", p)
+		}
+
+		port, proto := parts[1], "tcp"
+		if idx := strings.Index(port, "/"); idx != -1 {
+			port, proto = port[:idx], port[idx+1:]
+		}
+
+		containerPort, err := nat.NewPort(proto, port)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid container port in %q: %w", p, err)
+		}
+
+		exposed[containerPort] = struct{}{}
+		bindings[containerPort] = append(bindings[containerPort], nat.PortBinding{
+			HostIP:   "0.0.0.0",
+			HostPort: parts[0],
+		})
+	}
+
+	return exposed, bindings, nil
+}
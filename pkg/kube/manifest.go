@@ -0,0 +1,245 @@
+// Package kube projects a resolved app.Plan into Kubernetes manifests
+// (Deployment, Service, and an optional Ingress), the same plan-to-output
+// pattern pkg/generator uses for Dockerfiles.
+package kube
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/coollabsio/coolpack/pkg/app"
+)
+
+// defaultPort is used when the plan doesn't carry a detected port.
+const defaultPort = 3000
+
+// envVar is a rendered Kubernetes container env entry.
+type envVar struct {
+	Name  string
+	Value string
+}
+
+// Options configures the generated manifests beyond what's in the plan.
+type Options struct {
+	Name        string
+	Namespace   string
+	Image       string
+	Replicas    int
+	IngressHost string
+}
+
+// Generator renders Kubernetes manifests for a resolved plan.
+type Generator struct {
+	plan *app.Plan
+	opts Options
+}
+
+// New returns a Generator for plan using opts, mirroring generator.New's
+// constructor shape.
+func New(plan *app.Plan, opts Options) *Generator {
+	return &Generator{plan: plan, opts: opts}
+}
+
+// GenerateManifests renders deployment.yaml, service.yaml, and (if
+// opts.IngressHost is set) ingress.yaml.
+//
+// Note: plan.Metadata["custom_packages"] (the Dockerfile-side apt install
+// list) has no equivalent here — there's no image build step to bake them
+// into, and an initContainer can't make packages it installs visible to the
+// app container without a shared filesystem layer. custom_packages is
+// therefore ignored for kube output.
+func (g *Generator) GenerateManifests() (map[string]string, error) {
+	deployment, err := g.generateDeployment()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Deployment: %w", err)
+	}
+
+	service, err := g.generateService()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate Service: %w", err)
+	}
+
+	manifests := map[string]string{
+		"deployment.yaml": deployment,
+		"service.yaml":    service,
+	}
+
+	if g.opts.IngressHost != "" {
+		ingress, err := g.generateIngress()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ingress: %w", err)
+		}
+		manifests["ingress.yaml"] = ingress
+	}
+
+	return manifests, nil
+}
+
+func (g *Generator) port() int {
+	if p, ok := g.plan.Metadata["port"].(float64); ok && p > 0 {
+		return int(p)
+	}
+	if p, ok := g.plan.Metadata["port"].(int); ok && p > 0 {
+		return p
+	}
+	return defaultPort
+}
+
+func (g *Generator) command() []string {
+	if g.plan.StartCommand == "" {
+		return nil
+	}
+	return []string{"sh", "-c", g.plan.StartCommand}
+}
+
+// envVars flattens plan.BuildEnv into a sorted slice so the rendered
+// manifest is stable across regenerations.
+func (g *Generator) envVars() []envVar {
+	vars := make([]envVar, 0, len(g.plan.BuildEnv))
+	for k, v := range g.plan.BuildEnv {
+		vars = append(vars, envVar{Name: k, Value: v})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+const deploymentTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+    app: {{ .Name }}
+spec:
+  replicas: {{ .Replicas }}
+  selector:
+    matchLabels:
+      app: {{ .Name }}
+  template:
+    metadata:
+      labels:
+        app: {{ .Name }}
+    spec:
+      containers:
+        - name: {{ .Name }}
+          image: {{ .Image }}
+{{- if .Command }}
+          command: {{ .CommandJSON }}
+{{- end }}
+          ports:
+            - containerPort: {{ .Port }}
+{{- if .EnvVars }}
+          env:
+{{- range .EnvVars }}
+            - name: {{ .Name }}
+              value: {{ .Value | printf "%q" }}
+{{- end }}
+{{- end }}
+`
+
+const serviceTemplate = `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+spec:
+  selector:
+    app: {{ .Name }}
+  ports:
+    - port: {{ .Port }}
+      targetPort: {{ .Port }}
+`
+
+const ingressTemplate = `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+spec:
+  rules:
+    - host: {{ .Host }}
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: {{ .Name }}
+                port:
+                  number: {{ .Port }}
+`
+
+func (g *Generator) generateDeployment() (string, error) {
+	cmd := g.command()
+	data := struct {
+		Name        string
+		Namespace   string
+		Image       string
+		Replicas    int
+		Port        int
+		Command     []string
+		CommandJSON string
+		EnvVars     []envVar
+	}{
+		Name:      g.opts.Name,
+		Namespace: g.opts.Namespace,
+		Image:     g.opts.Image,
+		Replicas:  g.opts.Replicas,
+		Port:      g.port(),
+		Command:   cmd,
+		EnvVars:   g.envVars(),
+	}
+	if len(cmd) > 0 {
+		quoted := make([]string, len(cmd))
+		for i, c := range cmd {
+			quoted[i] = fmt.Sprintf("%q", c)
+		}
+		data.CommandJSON = "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	return renderTemplate("deployment", deploymentTemplate, data)
+}
+
+func (g *Generator) generateService() (string, error) {
+	data := struct {
+		Name      string
+		Namespace string
+		Port      int
+	}{
+		Name:      g.opts.Name,
+		Namespace: g.opts.Namespace,
+		Port:      g.port(),
+	}
+	return renderTemplate("service", serviceTemplate, data)
+}
+
+func (g *Generator) generateIngress() (string, error) {
+	data := struct {
+		Name      string
+		Namespace string
+		Host      string
+		Port      int
+	}{
+		Name:      g.opts.Name,
+		Namespace: g.opts.Namespace,
+		Host:      g.opts.IngressHost,
+		Port:      g.port(),
+	}
+	return renderTemplate("ingress", ingressTemplate, data)
+}
+
+func renderTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,56 @@
+package workspace
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coollabsio/coolpack/pkg/app"
+)
+
+// TestValuesApplyMergesBuildArgs guards the precedence Apply documents:
+// values.yaml's build_args land in plan.BuildEnv, overriding entries with the
+// same key and keeping the rest (prepare's --build-env merge runs after Apply,
+// so the CLI still wins over values.yaml).
+func TestValuesApplyMergesBuildArgs(t *testing.T) {
+	plan := &app.Plan{
+		BuildEnv: map[string]string{"EXISTING": "from-cli"},
+	}
+	values := &Values{
+		BuildArgs: map[string]string{"NODE_ENV": "production", "EXISTING": "from-values"},
+	}
+
+	values.Apply(plan)
+
+	want := map[string]string{"EXISTING": "from-values", "NODE_ENV": "production"}
+	if !reflect.DeepEqual(plan.BuildEnv, want) {
+		t.Errorf("plan.BuildEnv = %v, want %v", plan.BuildEnv, want)
+	}
+}
+
+func TestValuesApplySPAToggle(t *testing.T) {
+	trueVal, falseVal := true, false
+
+	plan := &app.Plan{Metadata: map[string]interface{}{"is_spa": true}}
+	(&Values{SPA: &falseVal}).Apply(plan)
+	if _, ok := plan.Metadata["is_spa"]; ok {
+		t.Errorf("plan.Metadata[is_spa] should be removed when SPA is explicitly false")
+	}
+
+	plan = &app.Plan{}
+	(&Values{SPA: &trueVal}).Apply(plan)
+	if v, _ := plan.Metadata["is_spa"].(bool); !v {
+		t.Errorf("plan.Metadata[is_spa] = %v, want true", plan.Metadata["is_spa"])
+	}
+}
+
+func TestValuesApplyLeavesUnsetFieldsAlone(t *testing.T) {
+	plan := &app.Plan{Metadata: map[string]interface{}{"base_image": "node:20"}}
+	(&Values{}).Apply(plan)
+
+	if plan.Metadata["base_image"] != "node:20" {
+		t.Errorf("plan.Metadata[base_image] = %v, want unchanged %q", plan.Metadata["base_image"], "node:20")
+	}
+	if plan.BuildEnv != nil {
+		t.Errorf("plan.BuildEnv = %v, want nil when no build_args are set", plan.BuildEnv)
+	}
+}
@@ -0,0 +1,175 @@
+// Package workspace manages the .coolpack directory as a multi-environment
+// project workspace: a set of named environments (e.g. dev, staging, prod),
+// each with its own resolved plan, generated Dockerfile, and values.yaml of
+// env-specific overrides.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coollabsio/coolpack/pkg/app"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEnv is the environment name used when a project hasn't created any
+// environments yet and none is specified.
+const DefaultEnv = "dev"
+
+// Root returns the .coolpack workspace directory for absPath.
+func Root(absPath string) string {
+	return filepath.Join(absPath, ".coolpack")
+}
+
+// EnvDir returns the directory holding plan.json, Dockerfile, and
+// values.yaml for the named environment.
+func EnvDir(absPath, name string) string {
+	return filepath.Join(Root(absPath), "env", name)
+}
+
+// activeFile records which environment `prepare`/`build`/`run` operate on
+// when --env isn't given explicitly.
+func activeFile(absPath string) string {
+	return filepath.Join(Root(absPath), "env", ".active")
+}
+
+// New creates a new environment directory with an empty values.yaml. It
+// fails if the environment already exists.
+func New(absPath, name string) error {
+	dir := EnvDir(absPath, name)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("environment %q already exists", name)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create environment %q: %w", name, err)
+	}
+
+	values := &Values{}
+	if err := values.Save(absPath, name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List returns the names of environments that exist under
+// .coolpack/env/, sorted by name.
+func List(absPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(Root(absPath), "env"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// GetActive returns the currently active environment name, defaulting to
+// DefaultEnv if none has been selected yet.
+func GetActive(absPath string) (string, error) {
+	data, err := os.ReadFile(activeFile(absPath))
+	if os.IsNotExist(err) {
+		return DefaultEnv, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read active environment: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetActive records name as the active environment, creating the
+// .coolpack/env directory if needed.
+func SetActive(absPath, name string) error {
+	if err := os.MkdirAll(filepath.Join(Root(absPath), "env"), 0755); err != nil {
+		return fmt.Errorf("failed to create env directory: %w", err)
+	}
+	if err := os.WriteFile(activeFile(absPath), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to set active environment: %w", err)
+	}
+	return nil
+}
+
+// Values holds the env-specific overrides stored in values.yaml: base
+// image, extra packages, build args, and the SPA toggle.
+type Values struct {
+	BaseImage string            `yaml:"base_image,omitempty"`
+	Packages  []string          `yaml:"packages,omitempty"`
+	BuildArgs map[string]string `yaml:"build_args,omitempty"`
+	SPA       *bool             `yaml:"spa,omitempty"`
+}
+
+// LoadValues reads values.yaml for the named environment. A missing file is
+// not an error: it simply means no overrides have been set yet.
+func LoadValues(absPath, name string) (*Values, error) {
+	data, err := os.ReadFile(filepath.Join(EnvDir(absPath, name), "values.yaml"))
+	if os.IsNotExist(err) {
+		return &Values{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values.yaml: %w", err)
+	}
+
+	var values Values
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values.yaml: %w", err)
+	}
+	return &values, nil
+}
+
+// Save writes v as the named environment's values.yaml.
+func (v *Values) Save(absPath, name string) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal values.yaml: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(EnvDir(absPath, name), "values.yaml"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write values.yaml: %w", err)
+	}
+	return nil
+}
+
+// Apply merges the environment overrides onto plan, following the same
+// CLI > env > detected precedence the rest of prepare uses: a value.yaml
+// setting wins over whatever was detected, but an explicit CLI flag (applied
+// after Apply) still wins over both.
+func (v *Values) Apply(plan *app.Plan) {
+	if plan.Metadata == nil {
+		plan.Metadata = make(map[string]interface{})
+	}
+
+	if v.BaseImage != "" {
+		plan.Metadata["base_image"] = v.BaseImage
+	}
+
+	if v.SPA != nil {
+		if *v.SPA {
+			plan.Metadata["is_spa"] = true
+		} else {
+			delete(plan.Metadata, "is_spa")
+		}
+	}
+
+	if len(v.Packages) > 0 {
+		plan.Metadata["custom_packages"] = v.Packages
+	}
+
+	if len(v.BuildArgs) > 0 {
+		if plan.BuildEnv == nil {
+			plan.BuildEnv = make(map[string]string, len(v.BuildArgs))
+		}
+		for k, val := range v.BuildArgs {
+			plan.BuildEnv[k] = val
+		}
+	}
+}